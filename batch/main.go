@@ -0,0 +1,77 @@
+// This command bulk-loads MathForge repo metadata from a directory of XML
+// or JSON files into the Elasticsearch "repos" index used by the search
+// service.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/olivere/elastic/v7"
+
+	"github.com/PetrefiedThunder/mathforge/internal/indexer"
+)
+
+func main() {
+	var (
+		dataDir   = flag.String("data-dir", "", "directory of repo metadata (.json/.xml) to ingest")
+		esURL     = flag.String("es-url", "http://elasticsearch:9200", "Elasticsearch URL")
+		index     = flag.String("index", "repos", "target Elasticsearch index")
+		batchSize = flag.Int("batch-size", 500, "number of documents per bulk request")
+		workers   = flag.Int("workers", 2, "number of bulk processor workers")
+		dryRun    = flag.Bool("dry-run", false, "validate records without writing to Elasticsearch")
+	)
+	flag.Parse()
+
+	if *dataDir == "" {
+		log.Fatal("-data-dir is required")
+	}
+
+	repos, err := indexer.LoadDir(*dataDir)
+	if err != nil {
+		log.Fatalf("Failed to load repo metadata: %v", err)
+	}
+	fmt.Printf("Loaded %d repos from %s\n", len(repos), *dataDir)
+
+	opts := indexer.DefaultOptions()
+	opts.Index = *index
+	opts.BatchSize = *batchSize
+	opts.Workers = *workers
+	opts.DryRun = *dryRun
+
+	ctx := context.Background()
+
+	if opts.DryRun {
+		count, err := indexer.Run(ctx, nil, repos, opts)
+		if err != nil {
+			log.Fatalf("Dry run failed: %v", err)
+		}
+		fmt.Printf("Dry run OK: %d repos would be indexed into %q\n", count, opts.Index)
+		return
+	}
+
+	es, err := elastic.NewClient(elastic.SetURL(*esURL))
+	if err != nil {
+		log.Fatalf("Failed to create Elasticsearch client: %v", err)
+	}
+
+	if err := indexer.EnsureMapping(ctx, es, opts); err != nil {
+		log.Fatalf("Failed to install index template: %v", err)
+	}
+
+	synonyms, err := indexer.LoadSynonyms(indexer.SynonymPath())
+	if err != nil {
+		log.Fatalf("Failed to load math synonym dictionary: %v", err)
+	}
+	if err := indexer.EnsureMathAnalyzer(ctx, es, opts, synonyms); err != nil {
+		log.Fatalf("Failed to install math analyzer template: %v", err)
+	}
+
+	count, err := indexer.Run(ctx, es, repos, opts)
+	if err != nil {
+		log.Fatalf("Bulk indexing failed: %v", err)
+	}
+	fmt.Printf("Indexed %d repos into %q\n", count, opts.Index)
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+  "bufio"
+  "context"
+  "flag"
+  "fmt"
+  "os"
+  "os/exec"
+  "strings"
+
+  "github.com/PetrefiedThunder/mathforge/internal/policy"
+)
+
+func main() {
+  configPath := flag.String("config", "/etc/mathforge/policy.yaml", "path to the policy config (YAML or JSON)")
+  sarif := flag.Bool("sarif", false, "emit violations as a SARIF document instead of plain text")
+  flag.Parse()
+
+  cfg, err := policy.LoadConfig(*configPath)
+  if err != nil {
+    // Fall back to the historical default so the hook still enforces
+    // something sensible if the config is missing.
+    cfg = policy.Config{AllowedTags: []string{"Algebra", "QM"}}
+  }
+  engine := policy.NewEngine(cfg)
+  ctx := context.Background()
+
+  var violations []policy.Violation
+  scanner := bufio.NewScanner(os.Stdin)
+  for scanner.Scan() {
+    parts := strings.Split(scanner.Text(), " ")
+    if len(parts) < 3 {
+      continue
+    }
+    repo := parts[1]
+    message, authorEmail := commitInfo(repo)
+    checked, err := engine.Run(ctx, policy.Input{
+      RepoName:      repo,
+      CommitMessage: message,
+      AuthorEmail:   authorEmail,
+    })
+    if err != nil {
+      fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+      os.Exit(1)
+    }
+    violations = append(violations, checked...)
+  }
+
+  if len(violations) == 0 {
+    return
+  }
+
+  if *sarif {
+    if err := policy.WriteSARIF(os.Stdout, violations); err != nil {
+      os.Exit(1)
+    }
+  } else {
+    policy.WriteHuman(os.Stderr, violations)
+  }
+  os.Exit(1)
+}
+
+// commitInfo looks up the subject line and author email of rev via git log,
+// so the conventional-commit and author-domain rules have real data to
+// check instead of always seeing empty fields. It returns empty strings,
+// not an error, if rev isn't a resolvable commit-ish, since a pre-receive
+// hook can't let a lookup failure block an otherwise-valid push.
+func commitInfo(rev string) (message, authorEmail string) {
+  return gitLog(rev, "%s"), gitLog(rev, "%ae")
+}
+
+func gitLog(rev, format string) string {
+  out, err := exec.Command("git", "log", "-1", "--format="+format, rev).Output()
+  if err != nil {
+    return ""
+  }
+  return strings.TrimSpace(string(out))
+}
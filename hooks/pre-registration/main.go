@@ -0,0 +1,124 @@
+package main
+
+import (
+  "fmt"
+  "log"
+  "net/http"
+  "strings"
+  "time"
+
+  "github.com/olivere/elastic/v7"
+
+  "github.com/PetrefiedThunder/mathforge/internal/adminauth"
+  "github.com/PetrefiedThunder/mathforge/internal/domains"
+  "github.com/PetrefiedThunder/mathforge/internal/policy"
+)
+
+const domainCacheTTL = 10 * time.Minute
+const domainCacheCapacity = 1000
+
+var domainStore *domains.Store
+var registrationEngine *policy.Engine
+
+func init() {
+  es, err := elastic.NewClient(elastic.SetURL("http://elasticsearch:9200"))
+  if err != nil {
+    log.Fatalf("Failed to create Elasticsearch client: %v", err)
+  }
+  domainStore = domains.NewStore(es, domainCacheTTL, domainCacheCapacity)
+
+  // Gate registration through the same policy engine the pre-receive
+  // hook uses, with an author-domain rule backed by this Store. The
+  // pre-receive hook checks author domains against the static list in
+  // its policy config, not against this Store.
+  registrationEngine = policy.NewEngine(policy.Config{})
+  registrationEngine.AddRule(policy.NewStoreBackedAuthorDomainRule(domainStore))
+}
+
+// PreRegister approves or denies a registration attempt based on whether
+// the requester's email domain is in the approved-domains index, logging
+// the decision to the registrations index either way.
+func PreRegister(w http.ResponseWriter, r *http.Request) {
+  email := r.FormValue("email")
+
+  violations, err := registrationEngine.Run(r.Context(), policy.Input{AuthorEmail: email})
+  if err != nil {
+    log.Printf("Domain lookup error: %v", err)
+    http.Error(w, "Registration temporarily unavailable", http.StatusServiceUnavailable)
+    return
+  }
+  // The author-domain rule passes an empty email, so deny that here.
+  approved := email != "" && len(violations) == 0
+
+  if err := domainStore.AuditDecision(r.Context(), email, approved); err != nil {
+    log.Printf("Failed to audit registration decision: %v", err)
+  }
+
+  if !approved {
+    http.Error(w, "Registration restricted to approved institutional domains", http.StatusForbidden)
+    return
+  }
+  fmt.Fprint(w, "OK")
+}
+
+// AddDomain handles POST /domains, approving a new institutional domain.
+func AddDomain(w http.ResponseWriter, r *http.Request) {
+  domain := r.FormValue("domain")
+  if domain == "" {
+    http.Error(w, "domain is required", http.StatusBadRequest)
+    return
+  }
+  if err := domainStore.Add(r.Context(), domain); err != nil {
+    log.Printf("Failed to add domain: %v", err)
+    http.Error(w, "Failed to add domain", http.StatusInternalServerError)
+    return
+  }
+  fmt.Fprint(w, "OK")
+}
+
+// RemoveDomain handles DELETE /domains/{d}, revoking approval for a
+// previously approved domain. d is the last path segment.
+func RemoveDomain(w http.ResponseWriter, r *http.Request) {
+  domain := lastPathSegment(r.URL.Path)
+  if domain == "" {
+    http.Error(w, "domain is required", http.StatusBadRequest)
+    return
+  }
+  if err := domainStore.Remove(r.Context(), domain); err != nil {
+    log.Printf("Failed to remove domain: %v", err)
+    http.Error(w, "Failed to remove domain", http.StatusInternalServerError)
+    return
+  }
+  fmt.Fprint(w, "OK")
+}
+
+func lastPathSegment(path string) string {
+  path = strings.TrimSuffix(path, "/")
+  if i := strings.LastIndex(path, "/"); i != -1 {
+    return path[i+1:]
+  }
+  return path
+}
+
+func main() {
+  http.HandleFunc("/register", PreRegister)
+  // The domain list controls who can register at all, so mutating it
+  // requires the shared admin token.
+  http.HandleFunc("/domains", adminauth.RequireToken(func(w http.ResponseWriter, r *http.Request) {
+    switch r.Method {
+    case http.MethodPost:
+      AddDomain(w, r)
+    default:
+      http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+    }
+  }))
+  http.HandleFunc("/domains/", adminauth.RequireToken(func(w http.ResponseWriter, r *http.Request) {
+    switch r.Method {
+    case http.MethodDelete:
+      RemoveDomain(w, r)
+    default:
+      http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+    }
+  }))
+  log.Fatal(http.ListenAndServe(":8081", nil))
+}
@@ -0,0 +1,28 @@
+// Package adminauth gates admin-only HTTP endpoints behind a shared
+// secret so they aren't reachable by anonymous callers.
+package adminauth
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"os"
+)
+
+// TokenEnvVar is the environment variable holding the shared admin token.
+const TokenEnvVar = "MATHFORGE_ADMIN_TOKEN"
+
+// RequireToken wraps next so it only runs when the request's
+// X-Admin-Token header matches the token configured via TokenEnvVar. If
+// no token is configured, every request is rejected — there is no way to
+// "accidentally" leave an admin endpoint open.
+func RequireToken(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		want := os.Getenv(TokenEnvVar)
+		got := r.Header.Get("X-Admin-Token")
+		if want == "" || subtle.ConstantTimeCompare([]byte(want), []byte(got)) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
@@ -0,0 +1,177 @@
+// Package domains looks up approved institutional email domains in
+// Elasticsearch, caches positive hits in-process, and records every
+// registration decision to an audit index. It backs the PreRegister
+// handler and the admin endpoints that manage the approved-domain list.
+package domains
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/olivere/elastic/v7"
+)
+
+const (
+	domainsIndex       = "domains"
+	registrationsIndex = "registrations"
+)
+
+// cacheEntry is a single positive lookup result along with its expiry.
+type cacheEntry struct {
+	expiresAt time.Time
+}
+
+// Store checks whether a domain is approved, backed by Elasticsearch and
+// fronted by an in-process LRU-with-TTL cache of positive hits.
+type Store struct {
+	es  *elastic.Client
+	ttl time.Duration
+
+	mu       sync.Mutex
+	cache    map[string]cacheEntry
+	order    []string
+	capacity int
+}
+
+// NewStore builds a Store querying es, caching positive hits for ttl and
+// holding at most capacity entries before evicting the least recently used.
+func NewStore(es *elastic.Client, ttl time.Duration, capacity int) *Store {
+	return &Store{
+		es:       es,
+		ttl:      ttl,
+		cache:    make(map[string]cacheEntry, capacity),
+		capacity: capacity,
+	}
+}
+
+// IsApproved reports whether domain has status=approved in the domains
+// index, consulting the cache first.
+func (s *Store) IsApproved(ctx context.Context, domain string) (bool, error) {
+	if s.cachedHit(domain) {
+		return true, nil
+	}
+
+	res, err := s.es.Search().
+		Index(domainsIndex).
+		Query(elastic.NewBoolQuery().
+			Filter(
+				elastic.NewTermQuery("domain.keyword", domain),
+				elastic.NewTermQuery("status", "approved"),
+			)).
+		Size(1).
+		Do(ctx)
+	if err != nil {
+		return false, fmt.Errorf("query domains index: %w", err)
+	}
+
+	approved := res.Hits.TotalHits.Value > 0
+	if approved {
+		s.cacheHit(domain)
+	}
+	return approved, nil
+}
+
+func (s *Store) cachedHit(domain string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.cache[domain]
+	if !ok {
+		return false
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(s.cache, domain)
+		return false
+	}
+	s.touch(domain)
+	return true
+}
+
+func (s *Store) cacheHit(domain string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.cache[domain]; !ok && len(s.cache) >= s.capacity && s.capacity > 0 {
+		s.evictOldest()
+	}
+	s.cache[domain] = cacheEntry{expiresAt: time.Now().Add(s.ttl)}
+	s.touch(domain)
+}
+
+// touch moves domain to the back of the LRU order, assuming s.mu is held.
+func (s *Store) touch(domain string) {
+	for i, d := range s.order {
+		if d == domain {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			break
+		}
+	}
+	s.order = append(s.order, domain)
+}
+
+// evictOldest removes the least recently used entry, assuming s.mu is held.
+func (s *Store) evictOldest() {
+	// Expired and removed entries leave their key in s.order; skip those
+	// so that a live entry is actually evicted.
+	for len(s.order) > 0 {
+		oldest := s.order[0]
+		s.order = s.order[1:]
+		if _, ok := s.cache[oldest]; ok {
+			delete(s.cache, oldest)
+			return
+		}
+	}
+}
+
+// Add records domain as approved in the domains index. Only positive hits
+// are cached, so there is no cached entry for it to invalidate.
+func (s *Store) Add(ctx context.Context, domain string) error {
+	_, err := s.es.Index().
+		Index(domainsIndex).
+		Id(domain).
+		BodyJson(map[string]interface{}{
+			"domain": domain,
+			"status": "approved",
+		}).
+		Do(ctx)
+	if err != nil {
+		return fmt.Errorf("add domain %q: %w", domain, err)
+	}
+	return nil
+}
+
+// Remove deletes domain from the domains index and evicts it from the
+// cache so a subsequent lookup re-checks Elasticsearch.
+func (s *Store) Remove(ctx context.Context, domain string) error {
+	_, err := s.es.Delete().
+		Index(domainsIndex).
+		Id(domain).
+		Do(ctx)
+	if err != nil {
+		return fmt.Errorf("remove domain %q: %w", domain, err)
+	}
+
+	s.mu.Lock()
+	delete(s.cache, domain)
+	s.mu.Unlock()
+	return nil
+}
+
+// AuditDecision logs a registration decision to the registrations index so
+// administrators can review denials after the fact.
+func (s *Store) AuditDecision(ctx context.Context, email string, approved bool) error {
+	_, err := s.es.Index().
+		Index(registrationsIndex).
+		BodyJson(map[string]interface{}{
+			"email":     email,
+			"approved":  approved,
+			"timestamp": time.Now().UTC(),
+		}).
+		Do(ctx)
+	if err != nil {
+		return fmt.Errorf("audit registration decision: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+// Package esclient wraps Elasticsearch client construction so that
+// services start up even when the cluster is momentarily unreachable, and
+// exposes a background health check other code can poll instead of
+// hitting Elasticsearch directly.
+package esclient
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/olivere/elastic/v7"
+)
+
+// Config controls how the client is constructed and health-checked.
+type Config struct {
+	URL                 string
+	Sniff               bool
+	HealthcheckInterval time.Duration
+	MaxRetries          int
+	Username            string // from ES_USERNAME if empty
+	Password            string // from ES_PASSWORD if empty
+	APIKey              string // from ES_API_KEY if empty
+}
+
+// DefaultConfig returns the settings appropriate for a single-node,
+// Docker Compose-style deployment: sniffing off (container DNS doesn't
+// resolve node addresses reported by the cluster) and a short health
+// check interval.
+func DefaultConfig(url string) Config {
+	return Config{
+		URL:                 url,
+		Sniff:               false,
+		HealthcheckInterval: 10 * time.Second,
+		MaxRetries:          5,
+	}
+}
+
+// Client wraps an *elastic.Client with a background ping loop so callers
+// can cheaply ask "is Elasticsearch up" without issuing their own request.
+type Client struct {
+	*elastic.Client
+
+	url string
+
+	mu      sync.RWMutex
+	healthy bool
+	lastErr error
+}
+
+// New builds a Client from cfg, does not fail if Elasticsearch is
+// momentarily down, and starts a background ping loop that keeps
+// Healthy() up to date.
+func New(ctx context.Context, cfg Config) (*Client, error) {
+	opts := []elastic.ClientOptionFunc{
+		elastic.SetURL(cfg.URL),
+		elastic.SetSniff(cfg.Sniff),
+		elastic.SetHealthcheckInterval(cfg.HealthcheckInterval),
+		// The client's own health check runs once at startup and makes
+		// NewClient fail when no node answers; pingLoop tracks health instead.
+		elastic.SetHealthcheck(false),
+		elastic.SetRetrier(boundedRetrier(cfg.MaxRetries)),
+	}
+
+	username := cfg.Username
+	if username == "" {
+		username = os.Getenv("ES_USERNAME")
+	}
+	password := cfg.Password
+	if password == "" {
+		password = os.Getenv("ES_PASSWORD")
+	}
+	if username != "" || password != "" {
+		opts = append(opts, elastic.SetBasicAuth(username, password))
+	}
+
+	apiKey := cfg.APIKey
+	if apiKey == "" {
+		apiKey = os.Getenv("ES_API_KEY")
+	}
+	if apiKey != "" {
+		opts = append(opts, elastic.SetHeaders(map[string][]string{
+			"Authorization": {"ApiKey " + apiKey},
+		}))
+	}
+
+	raw, err := elastic.NewClient(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("create elasticsearch client: %w", err)
+	}
+
+	c := &Client{Client: raw, url: cfg.URL}
+	go c.pingLoop(ctx, cfg.HealthcheckInterval)
+	return c, nil
+}
+
+// pingLoop periodically pings Elasticsearch and records the result so
+// Healthy() never has to block on a live network call.
+func (c *Client) pingLoop(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	c.ping(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.ping(ctx)
+		}
+	}
+}
+
+func (c *Client) ping(ctx context.Context) {
+	pingCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	_, _, err := c.Client.Ping(c.url).Do(pingCtx)
+
+	c.mu.Lock()
+	c.healthy = err == nil
+	c.lastErr = err
+	c.mu.Unlock()
+}
+
+// Healthy reports whether the last background ping succeeded.
+func (c *Client) Healthy() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.healthy
+}
+
+// LastError returns the error from the last background ping, or nil if
+// it succeeded.
+func (c *Client) LastError() error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.lastErr
+}
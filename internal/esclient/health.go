@@ -0,0 +1,36 @@
+package esclient
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+type healthResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// HealthzHandler always reports the service's own liveness, regardless of
+// Elasticsearch's state, so orchestrators don't restart a pod that's
+// merely waiting on a downstream dependency.
+func (c *Client) HealthzHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(healthResponse{Status: "ok"})
+}
+
+// ReadyzHandler reports whether Elasticsearch is reachable, returning 503
+// when it is not so load balancers stop routing traffic here.
+func (c *Client) ReadyzHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if !c.Healthy() {
+		w.Header().Set("Retry-After", "5")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		errMsg := ""
+		if err := c.LastError(); err != nil {
+			errMsg = err.Error()
+		}
+		json.NewEncoder(w).Encode(healthResponse{Status: "unavailable", Error: errMsg})
+		return
+	}
+	json.NewEncoder(w).Encode(healthResponse{Status: "ok"})
+}
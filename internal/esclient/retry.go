@@ -0,0 +1,23 @@
+package esclient
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/olivere/elastic/v7"
+)
+
+// boundedRetrier wraps an exponential backoff and stops retrying a
+// request once maxRetries has been reached, regardless of what the
+// backoff strategy would otherwise allow.
+func boundedRetrier(maxRetries int) elastic.Retrier {
+	backoff := elastic.NewExponentialBackoff(100*time.Millisecond, 8*time.Second)
+	return elastic.RetrierFunc(func(ctx context.Context, retry int, req *http.Request, resp *http.Response, err error) (time.Duration, bool, error) {
+		if maxRetries > 0 && retry >= maxRetries {
+			return 0, false, nil
+		}
+		wait, goahead := backoff.Next(retry)
+		return wait, goahead, nil
+	})
+}
@@ -0,0 +1,159 @@
+package indexer
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/olivere/elastic/v7"
+)
+
+// mathLaTeXCharFilterPatterns normalizes common LaTeX macros into plain words
+// before tokenization, so "\alpha" and "alpha" match the same query and
+// "\mathbb{R}" matches "reals".
+var mathLaTeXCharFilterPatterns = map[string]string{
+	`\alpha`:     "alpha",
+	`\beta`:      "beta",
+	`\gamma`:     "gamma",
+	`\delta`:     "delta",
+	`\mathbb{R}`: "reals",
+	`\mathbb{C}`: "complexnumbers",
+	`\mathbb{N}`: "naturalnumbers",
+	`\mathbb{Z}`: "integers",
+	`\infty`:     "infinity",
+	`\partial`:   "partial",
+	`\nabla`:     "nabla",
+	`\otimes`:    "tensorproduct",
+}
+
+// SynonymPath is the location of the math-terminology synonym dictionary,
+// overridable via MATHFORGE_SYNONYM_PATH so deployments can ship their own.
+func SynonymPath() string {
+	if p := os.Getenv("MATHFORGE_SYNONYM_PATH"); p != "" {
+		return p
+	}
+	return "/etc/mathforge/math-synonyms.csv"
+}
+
+// LoadSynonyms reads a CSV of synonym groups, one group per row (e.g.
+// "Hilbert space,H-space"), and renders each row into the "a, b, c" rule
+// form Elasticsearch's synonym filter expects, one rule per row. Rows with
+// fewer than two fields are skipped.
+// A missing file is not an error: it means no synonym dictionary has been
+// configured yet, so LoadSynonyms returns zero rules rather than blocking
+// callers (like the batch ingest tool) that don't otherwise depend on it.
+func LoadSynonyms(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open synonym file: %w", err)
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("parse synonym file: %w", err)
+	}
+
+	rules := make([]string, 0, len(records))
+	for _, rec := range records {
+		if len(rec) < 2 {
+			continue
+		}
+		rules = append(rules, strings.Join(rec, ", "))
+	}
+	return rules, nil
+}
+
+// EnsureMathAnalyzer installs an index template for opts.Index configuring
+// a LaTeX-aware analyzer: a char_filter normalizing LaTeX macros, a
+// tokenizer that keeps subscript/superscript groups intact, and a synonym
+// filter loaded from synonyms. ReloadAnalyzer re-reads the synonym file
+// and calls this again to pick up changes.
+func EnsureMathAnalyzer(ctx context.Context, es *elastic.Client, opts Options, synonyms []string) error {
+	charFilterMappings := make([]string, 0, len(mathLaTeXCharFilterPatterns))
+	for pattern, replacement := range mathLaTeXCharFilterPatterns {
+		charFilterMappings = append(charFilterMappings, fmt.Sprintf("%s => %s", pattern, replacement))
+	}
+
+	settings := map[string]interface{}{
+		"analysis": map[string]interface{}{
+			"char_filter": map[string]interface{}{
+				"latex_normalizer": map[string]interface{}{
+					"type":    "pattern_replace",
+					"pattern": `\\([a-zA-Z]+)(\{[^}]*\})?`,
+					// The mappings char_filter below handles the common
+					// cases explicitly; this pattern_replace filter is a
+					// fallback that strips the backslash (and any brace
+					// argument) off any macro the mappings don't cover,
+					// leaving just its bare name.
+					"replacement": "$1",
+				},
+				"latex_mappings": map[string]interface{}{
+					"type":     "mapping",
+					"mappings": charFilterMappings,
+				},
+			},
+			"tokenizer": map[string]interface{}{
+				"math_subsup_tokenizer": map[string]interface{}{
+					"type":    "pattern",
+					"pattern": `([\s,;:]+)`,
+				},
+			},
+			"filter": map[string]interface{}{
+				"math_synonyms": map[string]interface{}{
+					"type":     "synonym",
+					"synonyms": synonyms,
+				},
+			},
+			"analyzer": map[string]interface{}{
+				"math_text": map[string]interface{}{
+					"type":        "custom",
+					"char_filter": []string{"latex_mappings", "latex_normalizer"},
+					"tokenizer":   "math_subsup_tokenizer",
+					"filter":      []string{"lowercase", "math_synonyms"},
+				},
+			},
+		},
+	}
+
+	_, err := es.IndexPutTemplate(opts.Index + "-analyzer-template").
+		BodyJson(map[string]interface{}{
+			"index_patterns": []string{opts.Index},
+			"settings":       settings,
+		}).
+		Do(ctx)
+	if err != nil {
+		return fmt.Errorf("put math analyzer template: %w", err)
+	}
+	return nil
+}
+
+// ReloadAnalyzer re-reads the synonym dictionary at synonymPath and
+// reinstalls the analyzer template, then closes and reopens opts.Index so
+// the new analysis settings take effect. Elasticsearch requires a closed
+// index to change analysis settings.
+func ReloadAnalyzer(ctx context.Context, es *elastic.Client, opts Options, synonymPath string) error {
+	synonyms, err := LoadSynonyms(synonymPath)
+	if err != nil {
+		return err
+	}
+
+	if err := EnsureMathAnalyzer(ctx, es, opts, synonyms); err != nil {
+		return err
+	}
+
+	if _, err := es.CloseIndex(opts.Index).Do(ctx); err != nil {
+		return fmt.Errorf("close index: %w", err)
+	}
+	if _, err := es.OpenIndex(opts.Index).Do(ctx); err != nil {
+		return fmt.Errorf("open index: %w", err)
+	}
+	return nil
+}
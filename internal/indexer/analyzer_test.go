@@ -0,0 +1,18 @@
+package indexer
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadSynonymsMissingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "does-not-exist.csv")
+
+	synonyms, err := LoadSynonyms(path)
+	if err != nil {
+		t.Fatalf("expected a missing synonym file to be treated as zero synonyms, got error: %v", err)
+	}
+	if len(synonyms) != 0 {
+		t.Fatalf("expected no synonyms, got %+v", synonyms)
+	}
+}
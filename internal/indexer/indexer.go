@@ -0,0 +1,183 @@
+// Package indexer loads MathForge repo metadata from disk and streams it
+// into Elasticsearch in bulk. It backs the batch ingest tool and is kept
+// independent of the search service so it can run as an offline job.
+package indexer
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/olivere/elastic/v7"
+)
+
+// Repo is the typed record ingested into the "repos" index. Its JSON
+// tags mirror the field names searchHandler actually queries against
+// (title/abstract/body/topic_tags/author/year).
+type Repo struct {
+	Name     string   `json:"title" xml:"name"`
+	Topics   []string `json:"topic_tags" xml:"topics>topic"`
+	Author   string   `json:"author" xml:"author"`
+	Abstract string   `json:"abstract" xml:"abstract"`
+	Body     string   `json:"body" xml:"body"`
+	Year     int      `json:"year" xml:"year"`
+	State    string   `json:"state" xml:"state"`
+}
+
+// Options configures an ingest run.
+type Options struct {
+	Index      string
+	BatchSize  int
+	Workers    int
+	MaxRetries int
+	DryRun     bool
+}
+
+// DefaultOptions returns the options a standalone ingest run should use
+// absent any flags.
+func DefaultOptions() Options {
+	return Options{
+		Index:      "repos",
+		BatchSize:  500,
+		Workers:    2,
+		MaxRetries: 3,
+	}
+}
+
+// LoadDir walks dir and parses every *.json and *.xml file it finds into
+// Repo records, skipping anything else.
+func LoadDir(dir string) ([]Repo, error) {
+	var repos []Repo
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".json":
+			r, err := loadJSON(path)
+			if err != nil {
+				return fmt.Errorf("load %s: %w", path, err)
+			}
+			repos = append(repos, r...)
+		case ".xml":
+			r, err := loadXML(path)
+			if err != nil {
+				return fmt.Errorf("load %s: %w", path, err)
+			}
+			repos = append(repos, r...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return repos, nil
+}
+
+func loadJSON(path string) ([]Repo, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var repos []Repo
+	if err := json.NewDecoder(f).Decode(&repos); err != nil {
+		return nil, err
+	}
+	return repos, nil
+}
+
+func loadXML(path string) ([]Repo, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var doc struct {
+		Repos []Repo `xml:"repo"`
+	}
+	if err := xml.NewDecoder(f).Decode(&doc); err != nil {
+		return nil, err
+	}
+	return doc.Repos, nil
+}
+
+// EnsureMapping installs an index template for opts.Index if one does not
+// already exist, so repos land with the right field types before the first
+// document is bulk-indexed.
+func EnsureMapping(ctx context.Context, es *elastic.Client, opts Options) error {
+	exists, err := es.IndexTemplateExists(opts.Index + "-template").Do(ctx)
+	if err != nil {
+		return fmt.Errorf("check index template: %w", err)
+	}
+	if exists {
+		return nil
+	}
+
+	_, err = es.IndexPutTemplate(opts.Index + "-template").
+		BodyJson(map[string]interface{}{
+			"index_patterns": []string{opts.Index},
+			"mappings": map[string]interface{}{
+				"properties": map[string]interface{}{
+					"title":      map[string]interface{}{"type": "text"},
+					"topic_tags": map[string]interface{}{"type": "keyword"},
+					"author":     map[string]interface{}{"type": "keyword"},
+					"abstract":   map[string]interface{}{"type": "text"},
+					"body":       map[string]interface{}{"type": "text"},
+					"year":       map[string]interface{}{"type": "integer"},
+					"state":      map[string]interface{}{"type": "keyword"},
+				},
+			},
+		}).
+		Do(ctx)
+	if err != nil {
+		return fmt.Errorf("put index template: %w", err)
+	}
+	return nil
+}
+
+// Run streams repos into Elasticsearch using a BulkProcessor configured
+// from opts. In dry-run mode it validates the records and reports what
+// would be indexed without talking to Elasticsearch.
+func Run(ctx context.Context, es *elastic.Client, repos []Repo, opts Options) (indexed int, err error) {
+	if opts.DryRun {
+		for _, r := range repos {
+			if r.Name == "" {
+				return indexed, fmt.Errorf("repo missing name: %+v", r)
+			}
+			indexed++
+		}
+		return indexed, nil
+	}
+
+	processor, err := es.BulkProcessor().
+		Name("mathforge-indexer").
+		Workers(opts.Workers).
+		BulkActions(opts.BatchSize).
+		Backoff(bulkBackoff(opts.MaxRetries)).
+		Do(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("start bulk processor: %w", err)
+	}
+	defer processor.Close()
+
+	for _, r := range repos {
+		req := elastic.NewBulkIndexRequest().Index(opts.Index).Doc(r)
+		processor.Add(req)
+		indexed++
+	}
+
+	if err := processor.Flush(); err != nil {
+		return indexed, fmt.Errorf("flush bulk processor: %w", err)
+	}
+	return indexed, nil
+}
@@ -0,0 +1,73 @@
+package indexer
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadDirJSONAndXML(t *testing.T) {
+	dir := t.TempDir()
+
+	jsonRepos := []Repo{{Name: "Group Theory", Author: "ada"}}
+	jsonBytes, err := json.Marshal(jsonRepos)
+	if err != nil {
+		t.Fatalf("marshal json fixture: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "a.json"), jsonBytes, 0o644); err != nil {
+		t.Fatalf("write json fixture: %v", err)
+	}
+
+	xmlDoc := `<repos><repo><name>Quantum Mechanics</name><author>niels</author></repo></repos>`
+	if err := os.WriteFile(filepath.Join(dir, "b.xml"), []byte(xmlDoc), 0o644); err != nil {
+		t.Fatalf("write xml fixture: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "ignore.txt"), []byte("not a repo file"), 0o644); err != nil {
+		t.Fatalf("write ignored fixture: %v", err)
+	}
+
+	repos, err := LoadDir(dir)
+	if err != nil {
+		t.Fatalf("LoadDir returned error: %v", err)
+	}
+	if len(repos) != 2 {
+		t.Fatalf("expected 2 repos, got %d: %+v", len(repos), repos)
+	}
+
+	names := map[string]bool{}
+	for _, r := range repos {
+		names[r.Name] = true
+	}
+	if !names["Group Theory"] || !names["Quantum Mechanics"] {
+		t.Fatalf("expected both repos to be loaded, got %+v", repos)
+	}
+}
+
+func TestLoadDirMissingDir(t *testing.T) {
+	if _, err := LoadDir(filepath.Join(t.TempDir(), "does-not-exist")); err == nil {
+		t.Fatal("expected an error for a missing directory")
+	}
+}
+
+func TestRunDryRun(t *testing.T) {
+	repos := []Repo{{Name: "Group Theory"}, {Name: "Topology"}}
+
+	indexed, err := Run(context.Background(), nil, repos, Options{DryRun: true})
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if indexed != len(repos) {
+		t.Fatalf("expected %d indexed, got %d", len(repos), indexed)
+	}
+}
+
+func TestRunDryRunRejectsMissingName(t *testing.T) {
+	repos := []Repo{{Author: "ada"}}
+
+	if _, err := Run(context.Background(), nil, repos, Options{DryRun: true}); err == nil {
+		t.Fatal("expected an error for a repo with no name")
+	}
+}
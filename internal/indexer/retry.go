@@ -0,0 +1,30 @@
+package indexer
+
+import (
+	"time"
+
+	"github.com/olivere/elastic/v7"
+)
+
+// boundedBackoff wraps a Backoff and stops retrying once maxRetries has
+// been reached, regardless of what the underlying strategy would do.
+type boundedBackoff struct {
+	inner      elastic.Backoff
+	maxRetries int
+}
+
+func (b *boundedBackoff) Next(retry int) (time.Duration, bool) {
+	if b.maxRetries > 0 && retry >= b.maxRetries {
+		return 0, false
+	}
+	return b.inner.Next(retry)
+}
+
+// bulkBackoff builds the exponential backoff used by the BulkProcessor,
+// capped at maxRetries attempts per failed bulk request.
+func bulkBackoff(maxRetries int) elastic.Backoff {
+	return &boundedBackoff{
+		inner:      elastic.NewExponentialBackoff(100*time.Millisecond, 8*time.Second),
+		maxRetries: maxRetries,
+	}
+}
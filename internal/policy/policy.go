@@ -0,0 +1,238 @@
+// Package policy runs a set of pluggable rules over repository metadata
+// and commit-message lines, accumulating violations instead of failing on
+// the first match. It backs the pre-receive hook and the author-domain
+// gating used during registration.
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/PetrefiedThunder/mathforge/internal/domains"
+)
+
+// Input is one unit of work handed to every Rule: a single pushed ref line
+// (repo name, old/new sha, branch) plus any metadata a caller has on hand.
+type Input struct {
+	RepoName      string
+	CommitMessage string
+	AuthorEmail   string
+}
+
+// Violation describes one rule failure for one input.
+type Violation struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Rule is a single policy check. Check returns one Violation per failure;
+// a nil/empty slice means the input satisfies the rule. Rules that need
+// to consult an external system (e.g. an Elasticsearch-backed domain
+// list) take ctx and may return an error for infrastructure failures,
+// which callers should treat as distinct from a policy violation.
+type Rule interface {
+	Name() string
+	Check(ctx context.Context, in Input) ([]Violation, error)
+}
+
+// Config is the on-disk shape of a policy file (YAML or JSON).
+type Config struct {
+	AllowedTags          []string            `yaml:"allowed_tags" json:"allowed_tags"`
+	ConventionalCommits  bool                `yaml:"conventional_commits" json:"conventional_commits"`
+	AllowedAuthorDomains []string            `yaml:"allowed_author_domains" json:"allowed_author_domains"`
+	ReviewersByTag       map[string][]string `yaml:"reviewers_by_tag" json:"reviewers_by_tag"`
+}
+
+// LoadConfig reads a policy Config from a YAML or JSON file, selecting the
+// decoder by file extension.
+func LoadConfig(path string) (Config, error) {
+	var cfg Config
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, fmt.Errorf("read policy config: %w", err)
+	}
+
+	if strings.HasSuffix(path, ".json") {
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return cfg, fmt.Errorf("parse policy config: %w", err)
+		}
+		return cfg, nil
+	}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("parse policy config: %w", err)
+	}
+	return cfg, nil
+}
+
+// Engine runs every registered Rule over each Input it is given.
+type Engine struct {
+	rules []Rule
+}
+
+// NewEngine builds an Engine from the rules implied by cfg: tag
+// enforcement, conventional-commit checks, and author-domain gating.
+func NewEngine(cfg Config) *Engine {
+	e := &Engine{}
+	if len(cfg.AllowedTags) > 0 {
+		e.rules = append(e.rules, NewTagRule(cfg.AllowedTags))
+	}
+	if cfg.ConventionalCommits {
+		e.rules = append(e.rules, NewConventionalCommitRule())
+	}
+	if len(cfg.AllowedAuthorDomains) > 0 {
+		e.rules = append(e.rules, NewAuthorDomainRule(cfg.AllowedAuthorDomains))
+	}
+	return e
+}
+
+// AddRule registers an additional Rule, e.g. one with no config-driven
+// constructor.
+func (e *Engine) AddRule(r Rule) {
+	e.rules = append(e.rules, r)
+}
+
+// Run evaluates every rule against in and returns the combined
+// violations. It stops and returns early on the first rule error, since
+// that signals an infrastructure failure rather than a policy violation.
+func (e *Engine) Run(ctx context.Context, in Input) ([]Violation, error) {
+	var violations []Violation
+	for _, r := range e.rules {
+		checked, err := r.Check(ctx, in)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", r.Name(), err)
+		}
+		for _, v := range checked {
+			if v.Rule == "" {
+				v.Rule = r.Name()
+			}
+			violations = append(violations, v)
+		}
+	}
+	return violations, nil
+}
+
+// tagRule enforces that RepoName contains at least one of a fixed
+// vocabulary of topic tags.
+type tagRule struct {
+	allowed []string
+}
+
+// NewTagRule builds a Rule requiring RepoName to contain one of allowed.
+func NewTagRule(allowed []string) Rule {
+	return &tagRule{allowed: allowed}
+}
+
+func (r *tagRule) Name() string { return "topic-tag" }
+
+func (r *tagRule) Check(ctx context.Context, in Input) ([]Violation, error) {
+	for _, tag := range r.allowed {
+		if strings.Contains(in.RepoName, tag) {
+			return nil, nil
+		}
+	}
+	return []Violation{{
+		Message: fmt.Sprintf("repository %q must include an approved topic tag (one of %s)", in.RepoName, strings.Join(r.allowed, ", ")),
+	}}, nil
+}
+
+var conventionalCommitPattern = regexp.MustCompile(`^(feat|fix|chore|docs|refactor|test|perf|build|ci)(\([\w-]+\))?!?: .+`)
+
+// conventionalCommitRule enforces the Conventional Commits message format.
+type conventionalCommitRule struct{}
+
+// NewConventionalCommitRule builds a Rule requiring commit messages to
+// follow the Conventional Commits convention.
+func NewConventionalCommitRule() Rule {
+	return &conventionalCommitRule{}
+}
+
+func (r *conventionalCommitRule) Name() string { return "conventional-commit" }
+
+func (r *conventionalCommitRule) Check(ctx context.Context, in Input) ([]Violation, error) {
+	if in.CommitMessage == "" {
+		return nil, nil
+	}
+	if conventionalCommitPattern.MatchString(in.CommitMessage) {
+		return nil, nil
+	}
+	return []Violation{{
+		Message: fmt.Sprintf("commit message %q does not follow the Conventional Commits format", in.CommitMessage),
+	}}, nil
+}
+
+// authorDomainRule enforces that AuthorEmail belongs to one of a static
+// list of allowed domains loaded from the policy config. Prefer
+// NewStoreBackedAuthorDomainRule when an Elasticsearch domains.Store is
+// available, so the pre-receive hook can't diverge from what PreRegister
+// allows.
+type authorDomainRule struct {
+	domains []string
+}
+
+// NewAuthorDomainRule builds a Rule requiring AuthorEmail to end with one
+// of the given domains, e.g. "@math.org".
+func NewAuthorDomainRule(domains []string) Rule {
+	return &authorDomainRule{domains: domains}
+}
+
+func (r *authorDomainRule) Name() string { return "author-domain" }
+
+func (r *authorDomainRule) Check(ctx context.Context, in Input) ([]Violation, error) {
+	if in.AuthorEmail == "" {
+		return nil, nil
+	}
+	for _, d := range r.domains {
+		if strings.HasSuffix(in.AuthorEmail, d) {
+			return nil, nil
+		}
+	}
+	return []Violation{{
+		Message: fmt.Sprintf("author %q is not in an approved domain (one of %s)", in.AuthorEmail, strings.Join(r.domains, ", ")),
+	}}, nil
+}
+
+// storeBackedAuthorDomainRule enforces that AuthorEmail's domain is
+// approved according to an Elasticsearch-backed domains.Store — the same
+// store PreRegister's admin endpoints manage — instead of a static list.
+type storeBackedAuthorDomainRule struct {
+	store *domains.Store
+}
+
+// NewStoreBackedAuthorDomainRule builds a Rule requiring AuthorEmail's
+// domain to be approved in store.
+func NewStoreBackedAuthorDomainRule(store *domains.Store) Rule {
+	return &storeBackedAuthorDomainRule{store: store}
+}
+
+func (r *storeBackedAuthorDomainRule) Name() string { return "author-domain" }
+
+func (r *storeBackedAuthorDomainRule) Check(ctx context.Context, in Input) ([]Violation, error) {
+	if in.AuthorEmail == "" {
+		return nil, nil
+	}
+	approved, err := r.store.IsApproved(ctx, EmailDomain(in.AuthorEmail))
+	if err != nil {
+		return nil, fmt.Errorf("check author domain: %w", err)
+	}
+	if approved {
+		return nil, nil
+	}
+	return []Violation{{
+		Message: fmt.Sprintf("author %q is not in an approved domain", in.AuthorEmail),
+	}}, nil
+}
+
+// EmailDomain returns the domain portion of email, e.g. "math.org" for
+// "jane@math.org", or email unchanged if it has no "@".
+func EmailDomain(email string) string {
+	if i := strings.LastIndex(email, "@"); i != -1 {
+		return email[i+1:]
+	}
+	return email
+}
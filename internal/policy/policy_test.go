@@ -0,0 +1,121 @@
+package policy
+
+import (
+	"context"
+	"testing"
+)
+
+func TestTagRule(t *testing.T) {
+	rule := NewTagRule([]string{"Algebra", "QM"})
+
+	violations, err := rule.Check(context.Background(), Input{RepoName: "my-Algebra-repo"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(violations) != 0 {
+		t.Fatalf("expected no violations for an allowed tag, got %+v", violations)
+	}
+
+	violations, err = rule.Check(context.Background(), Input{RepoName: "untagged-repo"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(violations) != 1 {
+		t.Fatalf("expected one violation for a missing tag, got %+v", violations)
+	}
+}
+
+func TestConventionalCommitRule(t *testing.T) {
+	rule := NewConventionalCommitRule()
+
+	cases := []struct {
+		message       string
+		wantViolation bool
+	}{
+		{"feat(search): add faceted query DSL", false},
+		{"", false},
+		{"made some changes", true},
+	}
+
+	for _, c := range cases {
+		violations, err := rule.Check(context.Background(), Input{CommitMessage: c.message})
+		if err != nil {
+			t.Fatalf("unexpected error for message %q: %v", c.message, err)
+		}
+		if got := len(violations) > 0; got != c.wantViolation {
+			t.Errorf("message %q: got violation=%v, want %v", c.message, got, c.wantViolation)
+		}
+	}
+}
+
+func TestAuthorDomainRule(t *testing.T) {
+	rule := NewAuthorDomainRule([]string{"@math.org", "@physics.org"})
+
+	violations, err := rule.Check(context.Background(), Input{AuthorEmail: "ada@math.org"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(violations) != 0 {
+		t.Fatalf("expected no violations for an approved domain, got %+v", violations)
+	}
+
+	violations, err = rule.Check(context.Background(), Input{AuthorEmail: "ada@example.com"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(violations) != 1 {
+		t.Fatalf("expected one violation for a disallowed domain, got %+v", violations)
+	}
+}
+
+func TestEmailDomain(t *testing.T) {
+	cases := map[string]string{
+		"ada@math.org": "math.org",
+		"no-at-sign":   "no-at-sign",
+	}
+	for email, want := range cases {
+		if got := EmailDomain(email); got != want {
+			t.Errorf("EmailDomain(%q) = %q, want %q", email, got, want)
+		}
+	}
+}
+
+func TestEngineRunAccumulatesViolationsAcrossRules(t *testing.T) {
+	engine := NewEngine(Config{
+		AllowedTags:         []string{"Algebra"},
+		ConventionalCommits: true,
+	})
+
+	violations, err := engine.Run(context.Background(), Input{
+		RepoName:      "untagged-repo",
+		CommitMessage: "made some changes",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(violations) != 2 {
+		t.Fatalf("expected violations from both rules, got %+v", violations)
+	}
+}
+
+type failingRule struct{}
+
+func (failingRule) Name() string { return "failing-rule" }
+func (failingRule) Check(ctx context.Context, in Input) ([]Violation, error) {
+	return nil, errBoom
+}
+
+var errBoom = errBoomError{}
+
+type errBoomError struct{}
+
+func (errBoomError) Error() string { return "boom" }
+
+func TestEngineRunStopsOnRuleError(t *testing.T) {
+	engine := &Engine{}
+	engine.AddRule(failingRule{})
+
+	if _, err := engine.Run(context.Background(), Input{}); err == nil {
+		t.Fatal("expected Run to propagate the rule error")
+	}
+}
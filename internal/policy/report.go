@@ -0,0 +1,71 @@
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// WriteHuman prints violations as plain-text lines, one per violation, in
+// the style the original pre-receive hook used.
+func WriteHuman(w io.Writer, violations []Violation) {
+	for _, v := range violations {
+		fmt.Fprintf(w, "Error [%s]: %s\n", v.Rule, v.Message)
+	}
+}
+
+// sarifLog is a minimal SARIF 2.1.0 document containing one run with one
+// result per violation, enough for CI tools to annotate a diff.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name string `json:"name"`
+}
+
+type sarifResult struct {
+	RuleID  string       `json:"ruleId"`
+	Level   string       `json:"level"`
+	Message sarifMessage `json:"message"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+// WriteSARIF prints violations as a SARIF document for CI integration.
+func WriteSARIF(w io.Writer, violations []Violation) error {
+	results := make([]sarifResult, 0, len(violations))
+	for _, v := range violations {
+		results = append(results, sarifResult{
+			RuleID:  v.Rule,
+			Level:   "error",
+			Message: sarifMessage{Text: v.Message},
+		})
+	}
+
+	log := sarifLog{
+		Schema:  "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool:    sarifTool{Driver: sarifDriver{Name: "mathforge-policy"}},
+			Results: results,
+		}},
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(log)
+}
@@ -8,34 +8,126 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/olivere/elastic/v7"
+
+	"github.com/PetrefiedThunder/mathforge/internal/adminauth"
+	"github.com/PetrefiedThunder/mathforge/internal/esclient"
+	"github.com/PetrefiedThunder/mathforge/internal/indexer"
 )
 
-var es *elastic.Client
+var es *esclient.Client
 
 func init() {
-	// Initialize the Elasticsearch client.
-	// The URL uses 'elasticsearch' as the service name provided by Docker Compose.
+	// Initialize the Elasticsearch client. SetSniff(false) matters here:
+	// the URL uses 'elasticsearch' as the service name provided by Docker
+	// Compose, and sniffing would try to connect to node addresses the
+	// cluster reports that aren't resolvable from outside its network.
+	// Construction itself never fails on a down cluster; readiness is
+	// reported separately via /readyz.
 	var err error
-	es, err = elastic.NewClient(elastic.SetURL("http://elasticsearch:9200"))
+	es, err = esclient.New(context.Background(), esclient.DefaultConfig("http://elasticsearch:9200"))
 	if err != nil {
 		log.Fatalf("Failed to create Elasticsearch client: %v", err)
 	}
 }
 
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// searchResponse is the JSON shape returned by searchHandler. It wraps the
+// raw hits together with per-facet aggregation buckets so a client can
+// render filters without a second round trip.
+type searchResponse struct {
+	Total       int64                `json:"total"`
+	From        int                  `json:"from"`
+	Size        int                  `json:"size"`
+	Hits        []*elastic.SearchHit `json:"hits"`
+	TopicFacet  []facetBucket        `json:"topic_facets"`
+	AuthorFacet []facetBucket        `json:"author_facets"`
+}
+
+type facetBucket struct {
+	Key   string `json:"key"`
+	Count int64  `json:"count"`
+}
+
+// searchHandler answers /search with a faceted Elasticsearch query built
+// from the raw keyword plus a set of structured filters:
+//
+//	q          free-text search term (title/abstract/body)
+//	topic      pipe-separated list of topic tags, e.g. "Algebra|QM"
+//	author     author name filter
+//	year_from  inclusive lower bound on publication year
+//	year_to    inclusive upper bound on publication year
+//	state      repo lifecycle state, e.g. "active"
+//	from, size pagination offsets into the result set
 func searchHandler(w http.ResponseWriter, r *http.Request) {
-	// Extract query parameter 'q' for the search term.
-	q := r.URL.Query().Get("q")
-	if q == "" {
-		http.Error(w, "Query parameter 'q' is required", http.StatusBadRequest)
+	if !es.Healthy() {
+		w.Header().Set("Retry-After", "5")
+		http.Error(w, "Search is temporarily unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	q := r.URL.Query()
+
+	from, size, err := parsePagination(q.Get("from"), q.Get("size"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	// Execute the search query using Elasticsearch.
+	boolQuery := elastic.NewBoolQuery()
+
+	if term := q.Get("q"); term != "" {
+		boolQuery = boolQuery.Must(
+			elastic.NewMultiMatchQuery(term, "title", "abstract", "body").Type("best_fields"),
+		)
+	} else {
+		boolQuery = boolQuery.Must(elastic.NewMatchAllQuery())
+	}
+
+	if topics := q.Get("topic"); topics != "" {
+		boolQuery = boolQuery.Filter(elastic.NewTermsQuery("topic_tags", toInterfaceSlice(strings.Split(topics, "|"))...))
+	}
+
+	if author := q.Get("author"); author != "" {
+		boolQuery = boolQuery.Filter(elastic.NewTermQuery("author", author))
+	}
+
+	if state := q.Get("state"); state != "" {
+		boolQuery = boolQuery.Filter(elastic.NewTermQuery("state", state))
+	}
+
+	if yearFrom, yearTo := q.Get("year_from"), q.Get("year_to"); yearFrom != "" || yearTo != "" {
+		rangeQuery := elastic.NewRangeQuery("year")
+		if yearFrom != "" {
+			rangeQuery = rangeQuery.Gte(yearFrom)
+		}
+		if yearTo != "" {
+			rangeQuery = rangeQuery.Lte(yearTo)
+		}
+		boolQuery = boolQuery.Filter(rangeQuery)
+	}
+
+	highlight := elastic.NewHighlight().Fields(
+		elastic.NewHighlighterField("title"),
+		elastic.NewHighlighterField("abstract"),
+		elastic.NewHighlighterField("body"),
+	)
+
 	res, err := es.Search().
 		Index("repos"). // Specify the index to search.
-		Query(elastic.NewQueryStringQuery(q)).
+		Query(boolQuery).
+		Aggregation("by_topic", elastic.NewTermsAggregation().Field("topic_tags").Size(25)).
+		Aggregation("by_author", elastic.NewTermsAggregation().Field("author").Size(25)).
+		Highlight(highlight).
+		From(from).
+		Size(size).
 		Do(context.Background())
 	if err != nil {
 		log.Printf("Search error: %v", err)
@@ -43,18 +135,90 @@ func searchHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	resp := searchResponse{
+		Total:       res.Hits.TotalHits.Value,
+		From:        from,
+		Size:        size,
+		Hits:        res.Hits.Hits,
+		TopicFacet:  facetBuckets(res.Aggregations, "by_topic"),
+		AuthorFacet: facetBuckets(res.Aggregations, "by_author"),
+	}
+
 	// Encode the search results in JSON and send to the client.
-	if err := json.NewEncoder(w).Encode(res.Hits.Hits); err != nil {
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
 		log.Printf("JSON encoding error: %v", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 }
 
+// parsePagination validates the from/size query parameters, falling back to
+// sane defaults when they are absent.
+func parsePagination(rawFrom, rawSize string) (from, size int, err error) {
+	size = defaultPageSize
+	if rawSize != "" {
+		size, err = strconv.Atoi(rawSize)
+		if err != nil || size < 1 || size > maxPageSize {
+			return 0, 0, fmt.Errorf("size must be an integer between 1 and %d", maxPageSize)
+		}
+	}
+	if rawFrom != "" {
+		from, err = strconv.Atoi(rawFrom)
+		if err != nil || from < 0 {
+			return 0, 0, fmt.Errorf("from must be a non-negative integer")
+		}
+	}
+	return from, size, nil
+}
+
+// facetBuckets pulls the terms aggregation named aggName out of the raw
+// aggregation map and converts it into a flat list of key/count buckets.
+func facetBuckets(aggs elastic.Aggregations, aggName string) []facetBucket {
+	terms, found := aggs.Terms(aggName)
+	if !found {
+		return nil
+	}
+	buckets := make([]facetBucket, 0, len(terms.Buckets))
+	for _, b := range terms.Buckets {
+		buckets = append(buckets, facetBucket{Key: fmt.Sprintf("%v", b.Key), Count: b.DocCount})
+	}
+	return buckets
+}
+
+func toInterfaceSlice(values []string) []interface{} {
+	out := make([]interface{}, len(values))
+	for i, v := range values {
+		out[i] = v
+	}
+	return out
+}
+
+// reloadSynonymsHandler handles POST /admin/reload-synonyms: it re-reads
+// the math synonym dictionary and cycles the repos index so the updated
+// analyzer settings take effect.
+func reloadSynonymsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	opts := indexer.DefaultOptions()
+	if err := indexer.ReloadAnalyzer(r.Context(), es.Client, opts, indexer.SynonymPath()); err != nil {
+		log.Printf("Failed to reload synonyms: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprint(w, "OK")
+}
+
 func main() {
-	// Set up the /search endpoint.
+	// Set up the /search endpoint plus the health and admin endpoints used
+	// by load balancers, orchestrators, and operators.
 	http.HandleFunc("/search", searchHandler)
+	http.HandleFunc("/healthz", es.HealthzHandler)
+	http.HandleFunc("/readyz", es.ReadyzHandler)
+	http.HandleFunc("/admin/reload-synonyms", adminauth.RequireToken(reloadSynonymsHandler))
 	fmt.Println("Search service running on :8080")
 	// Start the HTTP server.
 	log.Fatal(http.ListenAndServe(":8080", nil))
-}
\ No newline at end of file
+}